@@ -0,0 +1,39 @@
+package main
+
+import "fmt"
+
+// Backend fetches bounty-labeled issues for every repository in an
+// organization. restBackend pages through the REST API (one call per repo,
+// plus pagination); graphqlBackend walks the same data in far fewer
+// round-trips via GitHub's GraphQL v4 API. includeRepos/excludeRepos
+// restrict which repos in the org are scanned; either may be nil.
+type Backend interface {
+	FetchIssues(org string, labels []string, token string, concurrency int, includeRepos, excludeRepos []string) ([]Issue, error)
+}
+
+// backendFor returns the Backend for the given -api value.
+func backendFor(api string) (Backend, error) {
+	switch api {
+	case "rest":
+		return restBackend{}, nil
+	case "graphql":
+		return graphqlBackend{}, nil
+	default:
+		return nil, fmt.Errorf("unknown -api %q (want rest or graphql)", api)
+	}
+}
+
+// restBackend is the original REST-based implementation: one call to list
+// org repos, then one paginated call per repo to list its issues.
+type restBackend struct{}
+
+func (restBackend) FetchIssues(org string, labels []string, token string, concurrency int, includeRepos, excludeRepos []string) ([]Issue, error) {
+	repos, err := getOrgRepos(org, token)
+	if err != nil {
+		return nil, err
+	}
+	repos = filterRepos(repos, includeRepos, excludeRepos)
+	fmt.Printf("Found %d repositories (concurrency=%d)\n", len(repos), concurrency)
+
+	return scanRepos(org, labels, token, repos, concurrency), nil
+}
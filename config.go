@@ -0,0 +1,108 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Target describes one bounty program to scan: an organization, the label
+// vocabulary that marks a bounty issue there (orgs disagree: "💎 Bounty",
+// "bounty", "$$$"), and an optional repo allow/deny list.
+type Target struct {
+	Org          string   `yaml:"org" json:"org"`
+	Labels       []string `yaml:"labels" json:"labels"`
+	IncludeRepos []string `yaml:"include_repos" json:"include_repos"`
+	ExcludeRepos []string `yaml:"exclude_repos" json:"exclude_repos"`
+}
+
+// Config lists every bounty program a single run should aggregate.
+type Config struct {
+	Targets []Target `yaml:"targets" json:"targets"`
+}
+
+// loadConfig reads a YAML or JSON config file, chosen by extension.
+func loadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var cfg Config
+	switch ext := filepath.Ext(path); ext {
+	case ".json":
+		err = json.Unmarshal(data, &cfg)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &cfg)
+	default:
+		return nil, fmt.Errorf("unrecognized config extension %q (want .yaml, .yml, or .json)", ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+
+	if len(cfg.Targets) == 0 {
+		return nil, fmt.Errorf("%s defines no targets", path)
+	}
+	for _, target := range cfg.Targets {
+		if len(target.Labels) == 0 {
+			return nil, fmt.Errorf("%s: target %q defines no labels", path, target.Org)
+		}
+	}
+
+	return &cfg, nil
+}
+
+// filterRepos applies a target's include/exclude lists. An empty include
+// list means "all repos"; exclude is applied after include and always wins.
+func filterRepos(repos []GitHubRepo, include, exclude []string) []GitHubRepo {
+	if len(include) == 0 && len(exclude) == 0 {
+		return repos
+	}
+
+	includeSet := toSet(include)
+	excludeSet := toSet(exclude)
+
+	filtered := make([]GitHubRepo, 0, len(repos))
+	for _, repo := range repos {
+		if len(includeSet) > 0 && !includeSet[repo.Name] {
+			continue
+		}
+		if excludeSet[repo.Name] {
+			continue
+		}
+		filtered = append(filtered, repo)
+	}
+	return filtered
+}
+
+func toSet(names []string) map[string]bool {
+	set := make(map[string]bool, len(names))
+	for _, n := range names {
+		set[n] = true
+	}
+	return set
+}
+
+// fetchTarget resolves one Target to its issues via backend, applying the
+// target's repo filters before scanning.
+func fetchTarget(backend Backend, target Target, token string, concurrency int) ([]Issue, error) {
+	issues, err := backend.FetchIssues(target.Org, target.Labels, token, concurrency, target.IncludeRepos, target.ExcludeRepos)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", target.Org, err)
+	}
+	return issues, nil
+}
+
+// describeLabels is used in log output, e.g. `"bounty", "$$$"`.
+func describeLabels(labels []string) string {
+	quoted := make([]string, len(labels))
+	for i, l := range labels {
+		quoted[i] = fmt.Sprintf("%q", l)
+	}
+	return strings.Join(quoted, ", ")
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFilterRepos(t *testing.T) {
+	repos := []GitHubRepo{{Name: "widget"}, {Name: "gizmo"}, {Name: "gadget"}}
+
+	cases := []struct {
+		name             string
+		include, exclude []string
+		want             []string
+	}{
+		{"no filters", nil, nil, []string{"widget", "gizmo", "gadget"}},
+		{"include only", []string{"widget", "gadget"}, nil, []string{"widget", "gadget"}},
+		{"exclude only", nil, []string{"gizmo"}, []string{"widget", "gadget"}},
+		{"include and exclude", []string{"widget", "gizmo"}, []string{"gizmo"}, []string{"widget"}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := filterRepos(repos, c.include, c.exclude)
+			if len(got) != len(c.want) {
+				t.Fatalf("got %v, want %v", names(got), c.want)
+			}
+			for i, repo := range got {
+				if repo.Name != c.want[i] {
+					t.Errorf("got %v, want %v", names(got), c.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestLoadConfigRejectsEmptyLabels(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, path, `
+targets:
+  - org: acme
+    labels: []
+`)
+
+	if _, err := loadConfig(path); err == nil {
+		t.Fatal("loadConfig = nil error, want one for a target with no labels (would match every open issue)")
+	}
+}
+
+func TestLoadConfigAcceptsValidTargets(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeFile(t, path, `
+targets:
+  - org: acme
+    labels: ["bounty"]
+`)
+
+	cfg, err := loadConfig(path)
+	if err != nil {
+		t.Fatalf("loadConfig: %v", err)
+	}
+	if len(cfg.Targets) != 1 || cfg.Targets[0].Org != "acme" {
+		t.Errorf("loadConfig = %+v, want one target for acme", cfg.Targets)
+	}
+}
+
+func writeFile(t *testing.T, path, contents string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+}
+
+func names(repos []GitHubRepo) []string {
+	out := make([]string, len(repos))
+	for i, r := range repos {
+		out[i] = r.Name
+	}
+	return out
+}
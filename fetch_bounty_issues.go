@@ -2,12 +2,17 @@ package main
 
 import (
 	"encoding/json"
+	"flag"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"os"
+	"strconv"
+	"sync"
 	"time"
+
+	"github.com/FuZoe/dn-institute/httpcache"
 )
 
 type Issue struct {
@@ -17,6 +22,7 @@ type Issue struct {
 	State        string   `json:"state"`
 	Labels       []string `json:"labels"`
 	CommentCount int      `json:"comment_count"`
+	Organization string   `json:"organization"`
 	Repository   string   `json:"repository"`
 	CreatedAt    string   `json:"created_at"`
 	UpdatedAt    string   `json:"updated_at"`
@@ -53,93 +59,277 @@ type GitHubIssue struct {
 
 var httpClient = &http.Client{Timeout: 30 * time.Second}
 
-const requestDelay = 500 * time.Millisecond
+// cacheStore is non-nil when -cache-dir is set, in which case httpClient's
+// transport has been wrapped with httpcache so repeated runs reuse 304
+// responses instead of spending primary rate limit.
+var cacheStore *httpcache.Store
+
+// rateLimiter tracks GitHub's primary rate limit across all workers and
+// blocks callers instead of racing past a 403/429.
+type rateLimiter struct {
+	mu        sync.Mutex
+	remaining int
+	resetAt   time.Time
+}
+
+// newRateLimiter starts out optimistic; the first response headers it sees
+// correct it to reality.
+func newRateLimiter() *rateLimiter {
+	return &rateLimiter{remaining: 1}
+}
+
+// lowWatermark is how many requests we keep in reserve before pausing
+// workers to wait out the window, so a burst of in-flight goroutines
+// doesn't overshoot the limit between header updates.
+const lowWatermark = 5
+
+func (r *rateLimiter) wait() {
+	r.mu.Lock()
+	remaining, resetAt := r.remaining, r.resetAt
+	r.mu.Unlock()
+
+	if remaining > lowWatermark || resetAt.IsZero() {
+		return
+	}
+
+	wait := time.Until(resetAt)
+	if wait > 0 {
+		fmt.Printf("  Rate limit low (%d remaining), waiting %v for reset...\n", remaining, wait.Round(time.Second))
+		time.Sleep(wait)
+	}
+}
+
+// resetDelay returns how long until the current rate-limit window resets.
+func (r *rateLimiter) resetDelay() time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return time.Until(r.resetAt)
+}
+
+func (r *rateLimiter) update(resp *http.Response) {
+	remaining, err := strconv.Atoi(resp.Header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return
+	}
+	resetUnix, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return
+	}
+
+	r.mu.Lock()
+	r.remaining = remaining
+	r.resetAt = time.Unix(resetUnix, 0)
+	r.mu.Unlock()
+}
+
+// retryAfter returns the server-requested backoff for a 403/429 response,
+// or zero if none was given.
+func retryAfter(resp *http.Response) time.Duration {
+	raw := resp.Header.Get("Retry-After")
+	if raw == "" {
+		return 0
+	}
+	seconds, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+var limiter = newRateLimiter()
 
 func main() {
 	org := "projectdiscovery"
 	label := "💎 Bounty"
-	outputFile := "bounty_issues.json"
+
+	concurrency := flag.Int("concurrency", 8, "number of repos to scan in parallel")
+	cacheDir := flag.String("cache-dir", "", "directory for a persistent ETag/Last-Modified cache (enables conditional requests)")
+	stale := flag.Bool("stale", false, "flag bounty issues that haven't been updated in -stale-days days")
+	staleDays := flag.Int("stale-days", 365, "age threshold in days for -stale")
+	openIssueTarget := flag.String("open-issue", "", "owner/repo to file a stale-bounty tracking issue against (requires -stale)")
+	format := flag.String("format", "json", "output format: json, csv, md, rss, or sqlite")
+	output := flag.String("output", "", "output file (defaults to bounty_issues.<format extension>)")
+	api := flag.String("api", "rest", "backend to fetch issues with: rest or graphql")
+	configPath := flag.String("config", "", "YAML/JSON config file listing multiple {org, labels[], include_repos[], exclude_repos[]} targets")
+	flag.Parse()
+	if *concurrency < 1 {
+		*concurrency = 1
+	}
+
+	outputFile := *output
+	if outputFile == "" {
+		outputFile = "bounty_issues." + formatExtension(*format)
+	}
+
+	if *cacheDir != "" {
+		store, err := httpcache.Open(*cacheDir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error opening cache dir: %v\n", err)
+			os.Exit(1)
+		}
+		cacheStore = store
+		httpClient.Transport = httpcache.NewTransport(store, httpClient.Transport)
+		defer func() {
+			if err := cacheStore.Save(); err != nil {
+				fmt.Fprintf(os.Stderr, "Error saving cache: %v\n", err)
+			}
+		}()
+	}
 
 	token := os.Getenv("GITHUB_TOKEN")
 	if token == "" {
 		fmt.Println("Note: GITHUB_TOKEN not set. Using unauthenticated requests (rate limited to 60/hour)")
 	}
 
-	fmt.Printf("Fetching repositories from organization: %s\n", org)
-
-	repos, err := getOrgRepos(org, token)
+	backend, err := backendFor(*api)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error fetching repos: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 
-	fmt.Printf("Found %d repositories\n", len(repos))
-
-	var allIssues []Issue
-
-	for i, repo := range repos {
-		repoName := repo.FullName
-		fmt.Printf("[%d/%d] Checking %s...\n", i+1, len(repos), repoName)
-
-		time.Sleep(requestDelay)
-		issues, err := getBountyIssues(org, repo.Name, label, token)
+	targets := []Target{{Org: org, Labels: []string{label}}}
+	if *configPath != "" {
+		cfg, err := loadConfig(*configPath)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: Error fetching issues from %s: %v\n", repoName, err)
-			continue
+			fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+			os.Exit(1)
 		}
+		targets = cfg.Targets
+	}
 
-		for _, ghIssue := range issues {
-			if ghIssue.PullRequest != nil {
-				continue
-			}
-			labels := make([]string, len(ghIssue.Labels))
-			for j, l := range ghIssue.Labels {
-				labels[j] = l.Name
-			}
-
-			issue := Issue{
-				Number:       ghIssue.Number,
-				Title:        ghIssue.Title,
-				URL:          ghIssue.HTMLURL,
-				State:        ghIssue.State,
-				Labels:       labels,
-				CommentCount: ghIssue.Comments,
-				Repository:   repoName,
-				CreatedAt:    ghIssue.CreatedAt,
-				UpdatedAt:    ghIssue.UpdatedAt,
-				Author:       ghIssue.User.Login,
-				Body:         ghIssue.Body,
-			}
-			allIssues = append(allIssues, issue)
+	var allIssues []Issue
+	for _, target := range targets {
+		fmt.Printf("Scanning %s for labels %s (api=%s)\n", target.Org, describeLabels(target.Labels), *api)
+		issues, err := fetchTarget(backend, target, token, *concurrency)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error fetching issues: %v\n", err)
+			os.Exit(1)
 		}
+		allIssues = append(allIssues, issues...)
 	}
 
 	fmt.Printf("\nTotal bounty issues found: %d\n", len(allIssues))
 
-	output, err := json.MarshalIndent(allIssues, "", "  ")
+	writer, err := writerFor(*format)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error marshaling JSON: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
+	if sw, ok := writer.(sqliteWriter); ok {
+		sw.DSN = outputFile
+		writer = sw
+	}
 
-	err = os.WriteFile(outputFile, output, 0644)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error writing file: %v\n", err)
+	if err := writeIssues(writer, outputFile, allIssues); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
 		os.Exit(1)
 	}
 
 	fmt.Printf("Results saved to: %s\n", outputFile)
+
+	if *stale {
+		if err := runStaleAudit(allIssues, *staleDays, *openIssueTarget, token); err != nil {
+			fmt.Fprintf(os.Stderr, "Error running stale-bounty audit: %v\n", err)
+			os.Exit(1)
+		}
+	}
+}
+
+// scanRepos fans out per-repo issue fetches across a worker pool sized by
+// concurrency and collects the results as they complete. When more than one
+// label is configured, labels are matched client-side (one fetch of all
+// open issues per repo) instead of multiplying API calls per label.
+func scanRepos(org string, labels []string, token string, repos []GitHubRepo, concurrency int) []Issue {
+	repoCh := make(chan GitHubRepo)
+	issueCh := make(chan Issue)
+	done := make(chan struct{})
+
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for repo := range repoCh {
+				issues, err := getIssues(org, repo.Name, labels, token)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: Error fetching issues from %s: %v\n", repo.FullName, err)
+					continue
+				}
+				for _, issue := range toIssues(org, repo.FullName, issues) {
+					issueCh <- issue
+				}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	go func() {
+		defer close(repoCh)
+		for i, repo := range repos {
+			fmt.Printf("[%d/%d] Queuing %s...\n", i+1, len(repos), repo.FullName)
+			repoCh <- repo
+		}
+	}()
+
+	var allIssues []Issue
+	for {
+		select {
+		case issue := <-issueCh:
+			allIssues = append(allIssues, issue)
+		case <-done:
+			// Drain any issues sent after done fired but before we observed it.
+			for {
+				select {
+				case issue := <-issueCh:
+					allIssues = append(allIssues, issue)
+				default:
+					return allIssues
+				}
+			}
+		}
+	}
+}
+
+// toIssues filters out pull requests (the issues API returns both) and
+// converts the remaining GitHub issues into our flattened Issue type.
+func toIssues(org, repoName string, ghIssues []GitHubIssue) []Issue {
+	issues := make([]Issue, 0, len(ghIssues))
+	for _, ghIssue := range ghIssues {
+		if ghIssue.PullRequest != nil {
+			continue
+		}
+		labels := make([]string, len(ghIssue.Labels))
+		for j, l := range ghIssue.Labels {
+			labels[j] = l.Name
+		}
+
+		issues = append(issues, Issue{
+			Number:       ghIssue.Number,
+			Title:        ghIssue.Title,
+			URL:          ghIssue.HTMLURL,
+			State:        ghIssue.State,
+			Labels:       labels,
+			CommentCount: ghIssue.Comments,
+			Organization: org,
+			Repository:   repoName,
+			CreatedAt:    ghIssue.CreatedAt,
+			UpdatedAt:    ghIssue.UpdatedAt,
+			Author:       ghIssue.User.Login,
+			Body:         ghIssue.Body,
+		})
+	}
+	return issues
 }
 
 func doRequest(reqURL, token string) ([]byte, error) {
 	maxRetries := 3
 
 	for attempt := 0; attempt < maxRetries; attempt++ {
-		if attempt > 0 {
-			waitTime := time.Duration(attempt*5) * time.Second
-			fmt.Printf("  Retrying in %v (attempt %d/%d)...\n", waitTime, attempt+1, maxRetries)
-			time.Sleep(waitTime)
-		}
+		limiter.wait()
 
 		req, err := http.NewRequest("GET", reqURL, nil)
 		if err != nil {
@@ -157,6 +347,8 @@ func doRequest(reqURL, token string) ([]byte, error) {
 			return nil, err
 		}
 
+		limiter.update(resp)
+
 		body, _ := io.ReadAll(resp.Body)
 		resp.Body.Close()
 
@@ -166,6 +358,14 @@ func doRequest(reqURL, token string) ([]byte, error) {
 
 		if resp.StatusCode == 429 || resp.StatusCode == 403 {
 			if attempt < maxRetries-1 {
+				wait := retryAfter(resp)
+				if wait == 0 {
+					wait = limiter.resetDelay()
+				}
+				if wait > 0 {
+					fmt.Printf("  Rate limited, waiting %v (attempt %d/%d)...\n", wait.Round(time.Second), attempt+1, maxRetries)
+					time.Sleep(wait)
+				}
 				continue
 			}
 		}
@@ -203,13 +403,22 @@ func getOrgRepos(org, token string) ([]GitHubRepo, error) {
 	return allRepos, nil
 }
 
-func getBountyIssues(org, repo, label, token string) ([]GitHubIssue, error) {
+// getIssues fetches open issues from repo. With a single label it filters
+// server-side via the issues API's `labels` parameter; with more than one
+// label it fetches all open issues once and matches labels client-side, so
+// a multi-label target doesn't multiply API calls per label.
+func getIssues(org, repo string, labels []string, token string) ([]GitHubIssue, error) {
+	labelParam := ""
+	if len(labels) == 1 {
+		labelParam = "&labels=" + url.QueryEscape(labels[0])
+	}
+
 	var allIssues []GitHubIssue
 	page := 1
 
 	for {
-		apiURL := fmt.Sprintf("https://api.github.com/repos/%s/%s/issues?state=open&labels=%s&per_page=100&page=%d",
-			org, repo, url.QueryEscape(label), page)
+		apiURL := fmt.Sprintf("https://api.github.com/repos/%s/%s/issues?state=open%s&per_page=100&page=%d",
+			org, repo, labelParam, page)
 
 		data, err := doRequest(apiURL, token)
 		if err != nil {
@@ -229,5 +438,25 @@ func getBountyIssues(org, repo, label, token string) ([]GitHubIssue, error) {
 		page++
 	}
 
+	if len(labels) > 1 {
+		allIssues = filterByAnyLabel(allIssues, labels)
+	}
+
 	return allIssues, nil
 }
+
+// filterByAnyLabel keeps issues that carry at least one of labels.
+func filterByAnyLabel(issues []GitHubIssue, labels []string) []GitHubIssue {
+	want := toSet(labels)
+
+	filtered := make([]GitHubIssue, 0, len(issues))
+	for _, issue := range issues {
+		for _, l := range issue.Labels {
+			if want[l.Name] {
+				filtered = append(filtered, issue)
+				break
+			}
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFilterByAnyLabel(t *testing.T) {
+	issues := []GitHubIssue{
+		{Number: 1, Labels: []GitHubLabel{{Name: "💎 Bounty"}}},
+		{Number: 2, Labels: []GitHubLabel{{Name: "bug"}}},
+		{Number: 3, Labels: []GitHubLabel{{Name: "bounty"}, {Name: "help wanted"}}},
+		{Number: 4, Labels: nil},
+	}
+
+	got := filterByAnyLabel(issues, []string{"💎 Bounty", "bounty", "$$$"})
+
+	want := map[int]bool{1: true, 3: true}
+	if len(got) != len(want) {
+		t.Fatalf("filterByAnyLabel returned %d issues, want %d: %+v", len(got), len(want), got)
+	}
+	for _, issue := range got {
+		if !want[issue.Number] {
+			t.Errorf("unexpected issue #%d in result", issue.Number)
+		}
+	}
+}
+
+func TestFilterByAnyLabelNoMatch(t *testing.T) {
+	issues := []GitHubIssue{{Number: 1, Labels: []GitHubLabel{{Name: "bug"}}}}
+
+	if got := filterByAnyLabel(issues, []string{"bounty"}); len(got) != 0 {
+		t.Fatalf("filterByAnyLabel = %+v, want none", got)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	cases := []struct {
+		name   string
+		header string
+		want   time.Duration
+	}{
+		{"missing", "", 0},
+		{"malformed", "soon", 0},
+		{"valid", "30", 30 * time.Second},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			resp := &http.Response{Header: http.Header{}}
+			if c.header != "" {
+				resp.Header.Set("Retry-After", c.header)
+			}
+			if got := retryAfter(resp); got != c.want {
+				t.Errorf("retryAfter(%q) = %v, want %v", c.header, got, c.want)
+			}
+		})
+	}
+}
+
+func TestRateLimiterWaitAboveWatermark(t *testing.T) {
+	r := &rateLimiter{remaining: lowWatermark + 1, resetAt: time.Now().Add(time.Hour)}
+
+	start := time.Now()
+	r.wait()
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("wait() blocked for %v while above the watermark", elapsed)
+	}
+}
+
+func TestRateLimiterWaitZeroResetAt(t *testing.T) {
+	r := &rateLimiter{remaining: 0}
+
+	start := time.Now()
+	r.wait()
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("wait() blocked for %v with a zero resetAt", elapsed)
+	}
+}
+
+func TestRateLimiterWaitBelowWatermark(t *testing.T) {
+	r := &rateLimiter{remaining: lowWatermark, resetAt: time.Now().Add(150 * time.Millisecond)}
+
+	start := time.Now()
+	r.wait()
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("wait() returned after %v, want to block roughly until resetAt", elapsed)
+	}
+}
+
+// roundTripFunc adapts a function to http.RoundTripper, for stubbing
+// httpClient in tests without spinning up a real listener.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
+
+func jsonResponse(t *testing.T, v any) *http.Response {
+	t.Helper()
+	body, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("marshal stub response: %v", err)
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{},
+		Body:       io.NopCloser(strings.NewReader(string(body))),
+	}
+}
+
+// TestScanReposCollectsAllIssuesWithoutLossOrDup drives scanRepos over a
+// stub transport serving a fixed set of repos, each with one page of
+// issues, and asserts the worker pool's fan-out/drain delivers every issue
+// exactly once with 4 workers.
+func TestScanReposCollectsAllIssuesWithoutLossOrDup(t *testing.T) {
+	origTransport := httpClient.Transport
+	defer func() { httpClient.Transport = origTransport }()
+
+	const repoCount = 12
+	var repos []GitHubRepo
+	want := make(map[string]bool)
+	for i := 0; i < repoCount; i++ {
+		name := "repo" + string(rune('a'+i))
+		repos = append(repos, GitHubRepo{Name: name, FullName: "acme/" + name})
+		want["acme/"+name+"#1"] = true
+	}
+
+	var mu sync.Mutex
+	seenPages := make(map[string]int)
+
+	httpClient.Transport = roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		// URL shape: https://api.github.com/repos/acme/<repo>/issues?state=open&labels=...&per_page=100&page=N
+		parts := strings.Split(req.URL.Path, "/")
+		repo := parts[3]
+
+		mu.Lock()
+		seenPages[repo]++
+		page := seenPages[repo]
+		mu.Unlock()
+
+		if page > 1 {
+			return jsonResponse(t, []GitHubIssue{}), nil
+		}
+		return jsonResponse(t, []GitHubIssue{{Number: 1, Title: "bounty"}}), nil
+	})
+
+	got := scanRepos("acme", []string{"💎 Bounty"}, "", repos, 4)
+
+	if len(got) != repoCount {
+		t.Fatalf("scanRepos returned %d issues, want %d (possible loss/dup): %+v", len(got), repoCount, got)
+	}
+
+	seen := make(map[string]int)
+	for _, issue := range got {
+		key := issue.Repository + "#" + string(rune('0'+issue.Number))
+		seen[key]++
+	}
+	for key := range want {
+		if seen[key] != 1 {
+			t.Errorf("issue %s seen %d times, want exactly 1", key, seen[key])
+		}
+	}
+}
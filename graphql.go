@@ -0,0 +1,369 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// graphqlEndpoint is GitHub's single GraphQL v4 endpoint; unlike REST there
+// is no per-resource URL, so the query itself selects what's returned.
+const graphqlEndpoint = "https://api.github.com/graphql"
+
+// issueFields is shared between bountyIssuesQuery and repoIssuesQuery so
+// both return the same shape into graphqlIssueNode.
+const issueFields = `
+            number
+            title
+            url
+            state
+            createdAt
+            updatedAt
+            body
+            author { login }
+            comments { totalCount }
+            labels(first: 10) { nodes { name } }
+`
+
+// bountyIssuesQuery enumerates every repository in an org and the first
+// page of its label-filtered open issues in one round-trip per page of
+// repositories. A repo whose issues.pageInfo.hasNextPage comes back true is
+// paged to completion afterwards with repoIssuesQuery.
+var bountyIssuesQuery = fmt.Sprintf(`
+query($org: String!, $labels: [String!], $cursor: String) {
+  organization(login: $org) {
+    repositories(first: 50, after: $cursor) {
+      pageInfo { hasNextPage endCursor }
+      nodes {
+        name
+        nameWithOwner
+        issues(first: 50, labels: $labels, states: OPEN) {
+          pageInfo { hasNextPage endCursor }
+          nodes {%s}
+        }
+      }
+    }
+  }
+  rateLimit { cost remaining resetAt }
+}`, issueFields)
+
+// repoIssuesQuery pages through the remaining open issues of a single repo
+// after bountyIssuesQuery's first page wasn't enough.
+var repoIssuesQuery = fmt.Sprintf(`
+query($owner: String!, $name: String!, $labels: [String!], $cursor: String) {
+  repository(owner: $owner, name: $name) {
+    issues(first: 50, after: $cursor, labels: $labels, states: OPEN) {
+      pageInfo { hasNextPage endCursor }
+      nodes {%s}
+    }
+  }
+  rateLimit { cost remaining resetAt }
+}`, issueFields)
+
+type graphqlRequest struct {
+	Query     string         `json:"query"`
+	Variables map[string]any `json:"variables"`
+}
+
+type graphqlRepoNode struct {
+	Name          string                 `json:"name"`
+	NameWithOwner string                 `json:"nameWithOwner"`
+	Issues        graphqlIssueConnection `json:"issues"`
+}
+
+type graphqlIssueConnection struct {
+	PageInfo graphqlPageInfo    `json:"pageInfo"`
+	Nodes    []graphqlIssueNode `json:"nodes"`
+}
+
+type graphqlPageInfo struct {
+	HasNextPage bool   `json:"hasNextPage"`
+	EndCursor   string `json:"endCursor"`
+}
+
+type graphqlIssueNode struct {
+	Number    int    `json:"number"`
+	Title     string `json:"title"`
+	URL       string `json:"url"`
+	State     string `json:"state"`
+	CreatedAt string `json:"createdAt"`
+	UpdatedAt string `json:"updatedAt"`
+	Body      string `json:"body"`
+	Author    struct {
+		Login string `json:"login"`
+	} `json:"author"`
+	Comments struct {
+		TotalCount int `json:"totalCount"`
+	} `json:"comments"`
+	Labels struct {
+		Nodes []GitHubLabel `json:"nodes"`
+	} `json:"labels"`
+}
+
+type graphqlRateLimit struct {
+	Cost      int    `json:"cost"`
+	Remaining int    `json:"remaining"`
+	ResetAt   string `json:"resetAt"`
+}
+
+type graphqlError struct {
+	Message string `json:"message"`
+}
+
+type graphqlResponse struct {
+	Data struct {
+		Organization struct {
+			Repositories struct {
+				PageInfo graphqlPageInfo   `json:"pageInfo"`
+				Nodes    []graphqlRepoNode `json:"nodes"`
+			} `json:"repositories"`
+		} `json:"organization"`
+		RateLimit graphqlRateLimit `json:"rateLimit"`
+	} `json:"data"`
+	Errors []graphqlError `json:"errors"`
+}
+
+// repoIssuesResponse is the response shape for repoIssuesQuery, which pages
+// a single repo's issues connection directly rather than through the org's
+// repositories connection.
+type repoIssuesResponse struct {
+	Data struct {
+		Repository struct {
+			Issues graphqlIssueConnection `json:"issues"`
+		} `json:"repository"`
+		RateLimit graphqlRateLimit `json:"rateLimit"`
+	} `json:"data"`
+	Errors []graphqlError `json:"errors"`
+}
+
+// graphqlBackend fetches bounty issues via GitHub's GraphQL v4 API, trading
+// REST's N+1 (one call per repo) for a handful of paginated queries whose
+// cost is accounted separately under rateLimit.cost / remaining / resetAt
+// rather than the REST primary rate limit.
+type graphqlBackend struct{}
+
+func (graphqlBackend) FetchIssues(org string, labels []string, token string, _ int, includeRepos, excludeRepos []string) ([]Issue, error) {
+	includeSet := toSet(includeRepos)
+	excludeSet := toSet(excludeRepos)
+
+	// The GraphQL issues(labels:) argument is an AND filter (an issue must
+	// carry every label), unlike REST's client-side OR. With more than one
+	// label, fetch unfiltered and match client-side via filterByAnyLabel,
+	// exactly as the REST backend does.
+	serverLabels := labels
+	if len(labels) > 1 {
+		serverLabels = nil
+	}
+
+	var allIssues []Issue
+	cursor := ""
+
+	for {
+		graphqlLimiter.wait()
+
+		resp, err := doGraphQLRequest(org, serverLabels, cursor, token)
+		if err != nil {
+			return nil, err
+		}
+		if len(resp.Errors) > 0 {
+			return nil, fmt.Errorf("graphql error: %s", resp.Errors[0].Message)
+		}
+		graphqlLimiter.update(resp.Data.RateLimit)
+
+		repos := resp.Data.Organization.Repositories
+		for _, repo := range repos.Nodes {
+			if len(includeSet) > 0 && !includeSet[repo.Name] {
+				continue
+			}
+			if excludeSet[repo.Name] {
+				continue
+			}
+
+			issueNodes := repo.Issues.Nodes
+			if repo.Issues.PageInfo.HasNextPage {
+				more, err := fetchRemainingIssues(org, repo.Name, serverLabels, repo.Issues.PageInfo.EndCursor, token)
+				if err != nil {
+					return nil, fmt.Errorf("%s: %w", repo.NameWithOwner, err)
+				}
+				issueNodes = append(issueNodes, more...)
+			}
+
+			ghIssues := graphqlIssuesToGitHubIssues(issueNodes)
+			if len(labels) > 1 {
+				ghIssues = filterByAnyLabel(ghIssues, labels)
+			}
+			allIssues = append(allIssues, toIssues(org, repo.NameWithOwner, ghIssues)...)
+		}
+
+		if !repos.PageInfo.HasNextPage {
+			break
+		}
+		cursor = repos.PageInfo.EndCursor
+	}
+
+	return allIssues, nil
+}
+
+// fetchRemainingIssues pages a single repo's issues connection to
+// completion, starting from cursor, after bountyIssuesQuery's first page of
+// 50 wasn't the whole story.
+func fetchRemainingIssues(org, repo string, labels []string, cursor, token string) ([]graphqlIssueNode, error) {
+	var nodes []graphqlIssueNode
+
+	for {
+		graphqlLimiter.wait()
+
+		resp, err := doRepoIssuesRequest(org, repo, labels, cursor, token)
+		if err != nil {
+			return nil, err
+		}
+		if len(resp.Errors) > 0 {
+			return nil, fmt.Errorf("graphql error: %s", resp.Errors[0].Message)
+		}
+		graphqlLimiter.update(resp.Data.RateLimit)
+
+		issues := resp.Data.Repository.Issues
+		nodes = append(nodes, issues.Nodes...)
+
+		if !issues.PageInfo.HasNextPage {
+			return nodes, nil
+		}
+		cursor = issues.PageInfo.EndCursor
+	}
+}
+
+// graphqlLabelPageSize mirrors the `labels(first: N)` argument in
+// issueFields; it's used only to detect and warn about truncation.
+const graphqlLabelPageSize = 10
+
+// graphqlIssuesToGitHubIssues adapts GraphQL issue nodes to the GitHubIssue
+// shape so both backends can share toIssues for the final Issue conversion.
+func graphqlIssuesToGitHubIssues(nodes []graphqlIssueNode) []GitHubIssue {
+	issues := make([]GitHubIssue, len(nodes))
+	for i, n := range nodes {
+		if len(n.Labels.Nodes) == graphqlLabelPageSize {
+			fmt.Printf("  Warning: issue #%d has >= %d labels, some may be missing from this result\n", n.Number, graphqlLabelPageSize)
+		}
+		issues[i] = GitHubIssue{
+			Number:    n.Number,
+			Title:     n.Title,
+			HTMLURL:   n.URL,
+			State:     n.State,
+			Labels:    n.Labels.Nodes,
+			Comments:  n.Comments.TotalCount,
+			CreatedAt: n.CreatedAt,
+			UpdatedAt: n.UpdatedAt,
+			Body:      n.Body,
+			User:      GitHubUser{Login: n.Author.Login},
+		}
+	}
+	return issues
+}
+
+func doGraphQLRequest(org string, labels []string, cursor, token string) (*graphqlResponse, error) {
+	var out graphqlResponse
+	err := postGraphQL(bountyIssuesQuery, map[string]any{
+		"org":    org,
+		"labels": labels,
+		"cursor": nullableCursor(cursor),
+	}, token, &out)
+	return &out, err
+}
+
+func doRepoIssuesRequest(owner, name string, labels []string, cursor, token string) (*repoIssuesResponse, error) {
+	var out repoIssuesResponse
+	err := postGraphQL(repoIssuesQuery, map[string]any{
+		"owner":  owner,
+		"name":   name,
+		"labels": labels,
+		"cursor": nullableCursor(cursor),
+	}, token, &out)
+	return &out, err
+}
+
+// postGraphQL executes query against graphqlEndpoint and decodes the
+// response into out (a pointer to one of the *Response types above).
+func postGraphQL(query string, variables map[string]any, token string, out any) error {
+	reqBody := graphqlRequest{Query: query, Variables: variables}
+
+	payload, err := json.Marshal(reqBody)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", graphqlEndpoint, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(body))
+	}
+
+	return json.Unmarshal(body, out)
+}
+
+// nullableCursor returns nil for an empty cursor so the first page's
+// $cursor variable serializes as GraphQL null rather than an empty string.
+func nullableCursor(cursor string) any {
+	if cursor == "" {
+		return nil
+	}
+	return cursor
+}
+
+// pointRateLimiter throttles GraphQL requests against GitHub's points-based
+// budget (rateLimit.cost/remaining/resetAt), the GraphQL analog of the REST
+// rateLimiter above. GraphQL calls don't go through doRequest, so they
+// can't share the REST limiter and need their own.
+type pointRateLimiter struct {
+	remaining int
+	resetAt   time.Time
+}
+
+var graphqlLimiter = &pointRateLimiter{remaining: 1}
+
+// graphqlLowWatermark is the points reserve kept before a query pauses to
+// wait out the window. It is larger than the REST lowWatermark because a
+// single GraphQL query here can cost more than one point.
+const graphqlLowWatermark = 20
+
+func (p *pointRateLimiter) wait() {
+	if p.remaining > graphqlLowWatermark || p.resetAt.IsZero() {
+		return
+	}
+
+	wait := time.Until(p.resetAt)
+	if wait > 0 {
+		fmt.Printf("  GraphQL rate limit low (%d points remaining), waiting %v for reset...\n", p.remaining, wait.Round(time.Second))
+		time.Sleep(wait)
+	}
+}
+
+// update records the latest points budget. It mirrors rateLimiter.update,
+// which likewise stays quiet here and only logs from wait() when a query
+// actually has to pause for it.
+func (p *pointRateLimiter) update(rl graphqlRateLimit) {
+	p.remaining = rl.Remaining
+	if resetAt, err := time.Parse(time.RFC3339, rl.ResetAt); err == nil {
+		p.resetAt = resetAt
+	}
+}
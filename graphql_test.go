@@ -0,0 +1,168 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNullableCursor(t *testing.T) {
+	if got := nullableCursor(""); got != nil {
+		t.Errorf("nullableCursor(\"\") = %v, want nil", got)
+	}
+	if got := nullableCursor("abc123"); got != "abc123" {
+		t.Errorf("nullableCursor(\"abc123\") = %v, want %q", got, "abc123")
+	}
+}
+
+func TestGraphqlIssuesToGitHubIssues(t *testing.T) {
+	nodes := []graphqlIssueNode{
+		{
+			Number:    7,
+			Title:     "Fix it",
+			URL:       "https://github.com/acme/widget/issues/7",
+			State:     "OPEN",
+			CreatedAt: "2024-01-01T00:00:00Z",
+			UpdatedAt: "2024-02-01T00:00:00Z",
+			Body:      "body text",
+		},
+	}
+	nodes[0].Author.Login = "carol"
+	nodes[0].Comments.TotalCount = 3
+	nodes[0].Labels.Nodes = []GitHubLabel{{Name: "bounty"}}
+
+	got := graphqlIssuesToGitHubIssues(nodes)
+	if len(got) != 1 {
+		t.Fatalf("got %d issues, want 1", len(got))
+	}
+
+	want := GitHubIssue{
+		Number:    7,
+		Title:     "Fix it",
+		HTMLURL:   "https://github.com/acme/widget/issues/7",
+		State:     "OPEN",
+		Labels:    []GitHubLabel{{Name: "bounty"}},
+		Comments:  3,
+		CreatedAt: "2024-01-01T00:00:00Z",
+		UpdatedAt: "2024-02-01T00:00:00Z",
+		Body:      "body text",
+		User:      GitHubUser{Login: "carol"},
+	}
+	if !reflect.DeepEqual(got[0], want) {
+		t.Errorf("graphqlIssuesToGitHubIssues = %+v, want %+v", got[0], want)
+	}
+}
+
+// fakeGraphQLRequest is enough of the wire format to tell the two queries
+// apart and read the variables the backend sent.
+type fakeGraphQLRequest struct {
+	Query     string         `json:"query"`
+	Variables map[string]any `json:"variables"`
+}
+
+// TestGraphqlBackendPaginatesAndFiltersClientSide stubs httpClient's
+// transport with a two-repo org: one repo's first page is complete, the
+// other's issues.hasNextPage forces fetchRemainingIssues to fall back to
+// repoIssuesQuery for a second page.
+func TestGraphqlBackendPaginatesAndFiltersClientSide(t *testing.T) {
+	origTransport := httpClient.Transport
+	defer func() { httpClient.Transport = origTransport }()
+
+	var sawOrgLabels, sawRepoLabels any
+	var repoQueryCalls int
+
+	httpClient.Transport = roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		body, _ := io.ReadAll(req.Body)
+		var fakeReq fakeGraphQLRequest
+		if err := json.Unmarshal(body, &fakeReq); err != nil {
+			t.Fatalf("decode request body: %v", err)
+		}
+
+		switch {
+		case strings.Contains(fakeReq.Query, "organization(login"):
+			sawOrgLabels = fakeReq.Variables["labels"]
+			return jsonResponse(t, map[string]any{
+				"data": map[string]any{
+					"organization": map[string]any{
+						"repositories": map[string]any{
+							"pageInfo": map[string]any{"hasNextPage": false, "endCursor": ""},
+							"nodes": []any{
+								map[string]any{
+									"name":          "widget",
+									"nameWithOwner": "acme/widget",
+									"issues": map[string]any{
+										"pageInfo": map[string]any{"hasNextPage": false, "endCursor": ""},
+										"nodes": []any{
+											map[string]any{"number": 1, "title": "bounty issue", "labels": map[string]any{"nodes": []any{map[string]any{"name": "bounty"}}}},
+										},
+									},
+								},
+								map[string]any{
+									"name":          "gizmo",
+									"nameWithOwner": "acme/gizmo",
+									"issues": map[string]any{
+										"pageInfo": map[string]any{"hasNextPage": true, "endCursor": "cursor1"},
+										"nodes": []any{
+											map[string]any{"number": 2, "title": "page one", "labels": map[string]any{"nodes": []any{map[string]any{"name": "$$$"}}}},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			}), nil
+
+		case strings.Contains(fakeReq.Query, "repository(owner"):
+			repoQueryCalls++
+			sawRepoLabels = fakeReq.Variables["labels"]
+			if fakeReq.Variables["cursor"] != "cursor1" {
+				t.Errorf("repo query cursor = %v, want %q", fakeReq.Variables["cursor"], "cursor1")
+			}
+			return jsonResponse(t, map[string]any{
+				"data": map[string]any{
+					"repository": map[string]any{
+						"issues": map[string]any{
+							"pageInfo": map[string]any{"hasNextPage": false, "endCursor": ""},
+							"nodes": []any{
+								map[string]any{"number": 3, "title": "page two", "labels": map[string]any{"nodes": []any{map[string]any{"name": "no match"}}}},
+							},
+						},
+					},
+				},
+			}), nil
+		}
+
+		t.Fatalf("unexpected graphql query: %s", fakeReq.Query)
+		return nil, nil
+	})
+
+	labels := []string{"bounty", "$$$"}
+	got, err := (graphqlBackend{}).FetchIssues("acme", labels, "", 1, nil, nil)
+	if err != nil {
+		t.Fatalf("FetchIssues: %v", err)
+	}
+
+	if repoQueryCalls != 1 {
+		t.Fatalf("repoIssuesQuery called %d times, want 1 (for gizmo's second page)", repoQueryCalls)
+	}
+	if sawOrgLabels != nil {
+		t.Errorf("org query sent labels=%v server-side, want nil (multi-label must filter client-side)", sawOrgLabels)
+	}
+	if sawRepoLabels != nil {
+		t.Errorf("repo query sent labels=%v server-side, want nil (multi-label must filter client-side)", sawRepoLabels)
+	}
+
+	// #1 (bounty) and #2 ($$$) match the label set; #3 (no match) must be
+	// dropped by the client-side filterByAnyLabel pass.
+	gotNumbers := make(map[int]bool)
+	for _, issue := range got {
+		gotNumbers[issue.Number] = true
+	}
+	if len(got) != 2 || !gotNumbers[1] || !gotNumbers[2] {
+		t.Fatalf("FetchIssues returned %+v, want issues #1 and #2 only", got)
+	}
+}
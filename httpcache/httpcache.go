@@ -0,0 +1,153 @@
+// Package httpcache provides a disk-backed cache for conditional HTTP GET
+// requests. It records the ETag and Last-Modified headers returned by each
+// response and replays the cached body on a subsequent 304, so repeated
+// runs against the same URLs stay cheap against rate-limited APIs such as
+// GitHub's.
+package httpcache
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// entry is the on-disk representation of one cached response.
+type entry struct {
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+	Body         []byte `json:"body"`
+}
+
+// Store is a JSON-file-backed cache keyed by request URL. It is safe for
+// concurrent use.
+type Store struct {
+	path string
+
+	mu      sync.Mutex
+	entries map[string]entry
+}
+
+// Open loads the cache file under dir, starting with an empty cache if the
+// file does not exist yet; the file itself is only written by Save. dir is
+// created if it does not already exist.
+func Open(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	s := &Store{
+		path:    filepath.Join(dir, "cache.json"),
+		entries: make(map[string]entry),
+	}
+
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &s.entries); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Save persists the cache to disk.
+func (s *Store) Save() error {
+	s.mu.Lock()
+	data, err := json.MarshalIndent(s.entries, "", "  ")
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.path, data, 0644)
+}
+
+func (s *Store) get(url string) (entry, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[url]
+	return e, ok
+}
+
+func (s *Store) put(url string, e entry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[url] = e
+}
+
+// Transport wraps an http.RoundTripper, attaching If-None-Match /
+// If-Modified-Since headers from the Store and serving cached bodies for
+// 304 Not Modified responses. GitHub does not count these conditional
+// requests that return 304 against the primary rate limit.
+type Transport struct {
+	Store *Store
+	Next  http.RoundTripper
+}
+
+// NewTransport wraps next with a cache backed by store. next defaults to
+// http.DefaultTransport if nil.
+func NewTransport(store *Store, next http.RoundTripper) *Transport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &Transport{Store: store, Next: next}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet {
+		return t.Next.RoundTrip(req)
+	}
+
+	key := req.URL.String()
+	cached, hasCached := t.Store.get(key)
+
+	if hasCached {
+		req = req.Clone(req.Context())
+		if cached.ETag != "" {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+		if cached.LastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.LastModified)
+		}
+	}
+
+	resp, err := t.Next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotModified && hasCached {
+		resp.Body.Close()
+		resp.StatusCode = http.StatusOK
+		resp.Status = "200 OK (cached)"
+		resp.Body = io.NopCloser(bytes.NewReader(cached.Body))
+		resp.ContentLength = int64(len(cached.Body))
+		return resp, nil
+	}
+
+	if resp.StatusCode == http.StatusOK {
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+
+		t.Store.put(key, entry{
+			ETag:         resp.Header.Get("ETag"),
+			LastModified: resp.Header.Get("Last-Modified"),
+			Body:         body,
+		})
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,113 @@
+package httpcache
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTransportServesCachedBodyOn304(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte("hello"))
+	}))
+	defer server.Close()
+
+	store, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	client := &http.Client{Transport: NewTransport(store, nil)}
+
+	for i := 0; i < 2; i++ {
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			t.Fatalf("Get #%d: %v", i, err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		if string(body) != "hello" {
+			t.Fatalf("Get #%d body = %q, want %q", i, body, "hello")
+		}
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Get #%d status = %d, want 200 (304s should be surfaced as 200 with cached body)", i, resp.StatusCode)
+		}
+	}
+
+	if requests != 2 {
+		t.Fatalf("server saw %d requests, want 2 (second should have been a conditional request)", requests)
+	}
+}
+
+func TestTransportSendsConditionalHeaders(t *testing.T) {
+	var sawIfNoneMatch, sawIfModifiedSince string
+	seen := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen++
+		if seen == 2 {
+			sawIfNoneMatch = r.Header.Get("If-None-Match")
+			sawIfModifiedSince = r.Header.Get("If-Modified-Since")
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"abc"`)
+		w.Header().Set("Last-Modified", "Mon, 01 Jan 2024 00:00:00 GMT")
+		w.Write([]byte("body"))
+	}))
+	defer server.Close()
+
+	store, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	client := &http.Client{Transport: NewTransport(store, nil)}
+
+	for i := 0; i < 2; i++ {
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			t.Fatalf("Get #%d: %v", i, err)
+		}
+		io.ReadAll(resp.Body)
+		resp.Body.Close()
+	}
+
+	if sawIfNoneMatch != `"abc"` {
+		t.Errorf("If-None-Match = %q, want %q", sawIfNoneMatch, `"abc"`)
+	}
+	if sawIfModifiedSince != "Mon, 01 Jan 2024 00:00:00 GMT" {
+		t.Errorf("If-Modified-Since = %q, want %q", sawIfModifiedSince, "Mon, 01 Jan 2024 00:00:00 GMT")
+	}
+}
+
+func TestStoreSaveAndReload(t *testing.T) {
+	dir := t.TempDir()
+
+	store, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	store.put("https://example.com/a", entry{ETag: `"v1"`, Body: []byte("cached")})
+	if err := store.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	reloaded, err := Open(dir)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	got, ok := reloaded.get("https://example.com/a")
+	if !ok {
+		t.Fatal("entry not found after reload")
+	}
+	if got.ETag != `"v1"` || string(got.Body) != "cached" {
+		t.Errorf("got %+v, want ETag=v1 Body=cached", got)
+	}
+}
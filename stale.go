@@ -0,0 +1,168 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// staleReportTitle is the fixed title used both to render the report and to
+// dedupe against any existing tracking issue for the same day.
+const staleReportTitle = "Stale bounty audit - %s"
+
+var staleReportTemplate = template.Must(template.New("stale").Parse(`## {{.Title}}
+
+{{len .Issues}} bounty issue(s) have not been updated in over {{.StaleDays}} days:
+
+{{range .Issues}}- [ ] [{{.Repository}}#{{.Number}}]({{.URL}}) - {{.Title}} (last updated {{.UpdatedAt}})
+{{end}}
+`))
+
+// staleReportData is the template context for the Markdown checklist.
+type staleReportData struct {
+	Title     string
+	StaleDays int
+	Issues    []Issue
+}
+
+// findStaleIssues returns the issues whose UpdatedAt is older than
+// staleDays, oldest first.
+func findStaleIssues(issues []Issue, staleDays int) []Issue {
+	cutoff := time.Now().AddDate(0, 0, -staleDays)
+
+	var stale []Issue
+	for _, issue := range issues {
+		updated, err := time.Parse(time.RFC3339, issue.UpdatedAt)
+		if err != nil {
+			continue
+		}
+		if updated.Before(cutoff) {
+			stale = append(stale, issue)
+		}
+	}
+	sort.SliceStable(stale, func(i, j int) bool {
+		ti, _ := time.Parse(time.RFC3339, stale[i].UpdatedAt)
+		tj, _ := time.Parse(time.RFC3339, stale[j].UpdatedAt)
+		return ti.Before(tj)
+	})
+	return stale
+}
+
+// renderStaleReport renders the Markdown checklist body for a stale-bounty
+// audit dated today.
+func renderStaleReport(issues []Issue, staleDays int) (title, body string, err error) {
+	title = fmt.Sprintf(staleReportTitle, time.Now().Format("2006-01-02"))
+
+	var buf bytes.Buffer
+	data := staleReportData{Title: title, StaleDays: staleDays, Issues: issues}
+	if err := staleReportTemplate.Execute(&buf, data); err != nil {
+		return "", "", err
+	}
+	return title, buf.String(), nil
+}
+
+// openIssueExists reports whether target (owner/repo) already has an open
+// issue with the given title, so we don't post a duplicate tracking issue
+// on every run.
+func openIssueExists(target, title, token string) (bool, error) {
+	page := 1
+
+	for {
+		apiURL := fmt.Sprintf("https://api.github.com/repos/%s/issues?state=open&per_page=100&page=%d", target, page)
+
+		data, err := doRequest(apiURL, token)
+		if err != nil {
+			return false, err
+		}
+
+		var issues []GitHubIssue
+		if err := json.Unmarshal(data, &issues); err != nil {
+			return false, err
+		}
+
+		if len(issues) == 0 {
+			return false, nil
+		}
+
+		for _, issue := range issues {
+			if strings.EqualFold(issue.Title, title) {
+				return true, nil
+			}
+		}
+		page++
+	}
+}
+
+// openTrackingIssue posts a new issue with the given title and body to
+// target (owner/repo).
+func openTrackingIssue(target, title, body, token string) error {
+	apiURL := fmt.Sprintf("https://api.github.com/repos/%s/issues", target)
+
+	payload, err := json.Marshal(map[string]string{
+		"title": title,
+		"body":  body,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", apiURL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return fmt.Errorf("HTTP %d creating tracking issue", resp.StatusCode)
+	}
+	return nil
+}
+
+// runStaleAudit flags issues older than staleDays, prints the Markdown
+// checklist, and optionally files it as a tracking issue against
+// openIssueTarget (skipping if a same-day report is already open).
+func runStaleAudit(issues []Issue, staleDays int, openIssueTarget, token string) error {
+	stale := findStaleIssues(issues, staleDays)
+	fmt.Printf("\nFound %d stale bounty issue(s) (older than %d days)\n", len(stale), staleDays)
+
+	title, body, err := renderStaleReport(stale, staleDays)
+	if err != nil {
+		return err
+	}
+	fmt.Println(body)
+
+	if openIssueTarget == "" {
+		return nil
+	}
+
+	exists, err := openIssueExists(openIssueTarget, title, token)
+	if err != nil {
+		return fmt.Errorf("checking for existing tracking issue: %w", err)
+	}
+	if exists {
+		fmt.Printf("Tracking issue %q already open on %s, skipping\n", title, openIssueTarget)
+		return nil
+	}
+
+	if err := openTrackingIssue(openIssueTarget, title, body, token); err != nil {
+		return fmt.Errorf("opening tracking issue: %w", err)
+	}
+	fmt.Printf("Opened tracking issue %q on %s\n", title, openIssueTarget)
+	return nil
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFindStaleIssues(t *testing.T) {
+	now := time.Now()
+	fresh := Issue{Number: 1, Repository: "acme/widget", UpdatedAt: now.AddDate(0, 0, -10).Format(time.RFC3339)}
+	stale := Issue{Number: 2, Repository: "acme/widget", UpdatedAt: now.AddDate(0, 0, -400).Format(time.RFC3339)}
+	unparseable := Issue{Number: 3, Repository: "acme/widget", UpdatedAt: "not-a-date"}
+
+	got := findStaleIssues([]Issue{fresh, stale, unparseable}, 365)
+
+	if len(got) != 1 || got[0].Number != 2 {
+		t.Fatalf("findStaleIssues = %+v, want only issue #2", got)
+	}
+}
+
+func TestFindStaleIssuesNoneStale(t *testing.T) {
+	now := time.Now()
+	issues := []Issue{
+		{Number: 1, UpdatedAt: now.AddDate(0, 0, -1).Format(time.RFC3339)},
+	}
+
+	if got := findStaleIssues(issues, 365); len(got) != 0 {
+		t.Fatalf("findStaleIssues = %+v, want none", got)
+	}
+}
+
+func TestRenderStaleReport(t *testing.T) {
+	now := time.Now()
+	issues := []Issue{
+		{Number: 42, Repository: "acme/widget", Title: "Old bug", URL: "https://github.com/acme/widget/issues/42", UpdatedAt: now.AddDate(0, 0, -400).Format(time.RFC3339)},
+	}
+
+	title, body, err := renderStaleReport(issues, 365)
+	if err != nil {
+		t.Fatalf("renderStaleReport: %v", err)
+	}
+
+	wantTitlePrefix := "Stale bounty audit - "
+	if !strings.HasPrefix(title, wantTitlePrefix) {
+		t.Errorf("title = %q, want prefix %q", title, wantTitlePrefix)
+	}
+	if !strings.Contains(body, "acme/widget#42") || !strings.Contains(body, "Old bug") {
+		t.Errorf("body missing issue reference:\n%s", body)
+	}
+}
+
+// TestOpenIssueExistsPaginatesPastFirstPage drives openIssueExists over a
+// stub serving a full 100-issue first page so that a match on a later page
+// isn't missed, guarding against a regression to the old single-fetch
+// per_page=100 query.
+func TestOpenIssueExistsPaginatesPastFirstPage(t *testing.T) {
+	origTransport := httpClient.Transport
+	defer func() { httpClient.Transport = origTransport }()
+
+	httpClient.Transport = roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		page := req.URL.Query().Get("page")
+		switch page {
+		case "1", "":
+			issues := make([]GitHubIssue, 100)
+			for i := range issues {
+				issues[i] = GitHubIssue{Number: i + 1, Title: "unrelated"}
+			}
+			return jsonResponse(t, issues), nil
+		case "2":
+			return jsonResponse(t, []GitHubIssue{{Number: 101, Title: "Stale bounty audit - 2024-01-01"}}), nil
+		default:
+			return jsonResponse(t, []GitHubIssue{}), nil
+		}
+	})
+
+	exists, err := openIssueExists("acme/widget", "Stale bounty audit - 2024-01-01", "")
+	if err != nil {
+		t.Fatalf("openIssueExists: %v", err)
+	}
+	if !exists {
+		t.Fatal("openIssueExists = false, want true (match is on the second page)")
+	}
+}
+
+func TestOpenIssueExistsNoMatch(t *testing.T) {
+	origTransport := httpClient.Transport
+	defer func() { httpClient.Transport = origTransport }()
+
+	httpClient.Transport = roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if req.URL.Query().Get("page") == "2" {
+			return jsonResponse(t, []GitHubIssue{}), nil
+		}
+		return jsonResponse(t, []GitHubIssue{{Number: 1, Title: "some other issue"}}), nil
+	})
+
+	exists, err := openIssueExists("acme/widget", "Stale bounty audit - 2024-01-01", "")
+	if err != nil {
+		t.Fatalf("openIssueExists: %v", err)
+	}
+	if exists {
+		t.Fatal("openIssueExists = true, want false")
+	}
+}
@@ -0,0 +1,262 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// IssueWriter serializes a set of issues to w in a specific format.
+type IssueWriter interface {
+	Write(w io.Writer, issues []Issue) error
+}
+
+// formatExtension returns the conventional file extension for a -format
+// value, used to pick a default output filename.
+func formatExtension(format string) string {
+	switch format {
+	case "md":
+		return "md"
+	case "rss":
+		return "xml"
+	case "sqlite":
+		return "db"
+	case "csv":
+		return "csv"
+	default:
+		return "json"
+	}
+}
+
+// writeIssues renders issues with w and saves the result to path. The
+// sqlite writer manages its own file handle via its DSN and ignores w's
+// destination.
+func writeIssues(w IssueWriter, path string, issues []Issue) error {
+	if sw, ok := w.(sqliteWriter); ok {
+		return sw.Write(nil, issues)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return w.Write(f, issues)
+}
+
+// writerFor returns the IssueWriter for the given -format value, or an
+// error if the format isn't recognized.
+func writerFor(format string) (IssueWriter, error) {
+	switch format {
+	case "json":
+		return jsonWriter{}, nil
+	case "csv":
+		return csvWriter{}, nil
+	case "md":
+		return markdownWriter{}, nil
+	case "rss":
+		return rssWriter{}, nil
+	case "sqlite":
+		return sqliteWriter{}, nil
+	default:
+		return nil, fmt.Errorf("unknown -format %q (want json, csv, md, rss, or sqlite)", format)
+	}
+}
+
+type jsonWriter struct{}
+
+func (jsonWriter) Write(w io.Writer, issues []Issue) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(issues)
+}
+
+type csvWriter struct{}
+
+func (csvWriter) Write(w io.Writer, issues []Issue) error {
+	cw := csv.NewWriter(w)
+	defer cw.Flush()
+
+	header := []string{"repository", "number", "title", "url", "state", "labels", "comment_count", "author", "created_at", "updated_at"}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	for _, issue := range issues {
+		record := []string{
+			issue.Repository,
+			strconv.Itoa(issue.Number),
+			issue.Title,
+			issue.URL,
+			issue.State,
+			joinLabels(issue.Labels),
+			strconv.Itoa(issue.CommentCount),
+			issue.Author,
+			issue.CreatedAt,
+			issue.UpdatedAt,
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	return cw.Error()
+}
+
+func joinLabels(labels []string) string {
+	out := ""
+	for i, l := range labels {
+		if i > 0 {
+			out += ";"
+		}
+		out += l
+	}
+	return out
+}
+
+type markdownWriter struct{}
+
+func (markdownWriter) Write(w io.Writer, issues []Issue) error {
+	byRepo := make(map[string][]Issue)
+	var repos []string
+	for _, issue := range issues {
+		if _, ok := byRepo[issue.Repository]; !ok {
+			repos = append(repos, issue.Repository)
+		}
+		byRepo[issue.Repository] = append(byRepo[issue.Repository], issue)
+	}
+	sort.Strings(repos)
+
+	for _, repo := range repos {
+		if _, err := fmt.Fprintf(w, "## %s\n\n", repo); err != nil {
+			return err
+		}
+		for _, issue := range byRepo[repo] {
+			if _, err := fmt.Fprintf(w, "- [#%d %s](%s) `%s`\n", issue.Number, issue.Title, issue.URL, joinLabels(issue.Labels)); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintln(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rssFeed and rssEntry model just enough of the Atom spec to produce a
+// readable per-issue feed keyed on URL and UpdatedAt.
+type rssFeed struct {
+	XMLName xml.Name   `xml:"feed"`
+	Xmlns   string     `xml:"xmlns,attr"`
+	Title   string     `xml:"title"`
+	Updated string     `xml:"updated"`
+	Entries []rssEntry `xml:"entry"`
+}
+
+type rssEntry struct {
+	Title   string  `xml:"title"`
+	ID      string  `xml:"id"`
+	Link    rssLink `xml:"link"`
+	Updated string  `xml:"updated"`
+	Summary string  `xml:"summary"`
+}
+
+type rssLink struct {
+	Href string `xml:"href,attr"`
+}
+
+type rssWriter struct{}
+
+func (rssWriter) Write(w io.Writer, issues []Issue) error {
+	feed := rssFeed{
+		Xmlns:   "http://www.w3.org/2005/Atom",
+		Title:   "Bounty issues",
+		Updated: time.Now().UTC().Format(time.RFC3339),
+	}
+	for _, issue := range issues {
+		feed.Entries = append(feed.Entries, rssEntry{
+			Title:   fmt.Sprintf("%s: %s", issue.Repository, issue.Title),
+			ID:      issue.URL,
+			Link:    rssLink{Href: issue.URL},
+			Updated: issue.UpdatedAt,
+			Summary: issue.Body,
+		})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	return enc.Encode(feed)
+}
+
+// sqliteWriter upserts into an issues table keyed by (repository, number)
+// so historical runs accumulate in a single file and diffs can be computed
+// across runs.
+type sqliteWriter struct {
+	// DSN is the sqlite file path. main sets this from -output when
+	// -format=sqlite.
+	DSN string
+}
+
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS issues (
+	repository    TEXT NOT NULL,
+	number        INTEGER NOT NULL,
+	title         TEXT,
+	url           TEXT,
+	state         TEXT,
+	labels        TEXT,
+	comment_count INTEGER,
+	author        TEXT,
+	created_at    TEXT,
+	updated_at    TEXT,
+	body          TEXT,
+	PRIMARY KEY (repository, number)
+);
+`
+
+func (sw sqliteWriter) Write(_ io.Writer, issues []Issue) error {
+	db, err := sql.Open("sqlite3", sw.DSN)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		return err
+	}
+
+	stmt, err := db.Prepare(`
+		INSERT INTO issues (repository, number, title, url, state, labels, comment_count, author, created_at, updated_at, body)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(repository, number) DO UPDATE SET
+			title = excluded.title, url = excluded.url, state = excluded.state,
+			labels = excluded.labels, comment_count = excluded.comment_count,
+			author = excluded.author, updated_at = excluded.updated_at, body = excluded.body
+	`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, issue := range issues {
+		_, err := stmt.Exec(issue.Repository, issue.Number, issue.Title, issue.URL, issue.State,
+			joinLabels(issue.Labels), issue.CommentCount, issue.Author, issue.CreatedAt, issue.UpdatedAt, issue.Body)
+		if err != nil {
+			return fmt.Errorf("upserting %s#%d: %w", issue.Repository, issue.Number, err)
+		}
+	}
+
+	return nil
+}
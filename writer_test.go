@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bytes"
+	"database/sql"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func sampleIssues() []Issue {
+	return []Issue{
+		{
+			Number:       1,
+			Title:        "Fix race condition",
+			URL:          "https://github.com/acme/widget/issues/1",
+			State:        "open",
+			Labels:       []string{"💎 Bounty", "bug"},
+			CommentCount: 2,
+			Organization: "acme",
+			Repository:   "acme/widget",
+			CreatedAt:    "2024-01-01T00:00:00Z",
+			UpdatedAt:    "2024-06-01T00:00:00Z",
+			Author:       "alice",
+			Body:         "steps to reproduce",
+		},
+		{
+			Number:       2,
+			Title:        "Add retry logic",
+			URL:          "https://github.com/acme/gizmo/issues/2",
+			State:        "open",
+			Labels:       []string{"bounty"},
+			CommentCount: 0,
+			Organization: "acme",
+			Repository:   "acme/gizmo",
+			CreatedAt:    "2024-02-01T00:00:00Z",
+			UpdatedAt:    "2024-02-15T00:00:00Z",
+			Author:       "bob",
+			Body:         "",
+		},
+	}
+}
+
+func TestCSVWriter(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (csvWriter{}).Write(&buf, sampleIssues()); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	out := buf.String()
+	lines := strings.Split(strings.TrimRight(out, "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want 3 (header + 2 rows):\n%s", len(lines), out)
+	}
+	if !strings.HasPrefix(lines[0], "repository,number,title") {
+		t.Errorf("unexpected header: %q", lines[0])
+	}
+	if !strings.Contains(lines[1], "acme/widget") || !strings.Contains(lines[1], "💎 Bounty;bug") {
+		t.Errorf("row missing repository/labels: %q", lines[1])
+	}
+}
+
+func TestMarkdownWriter(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (markdownWriter{}).Write(&buf, sampleIssues()); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	out := buf.String()
+	wantHeadings := []string{"## acme/gizmo", "## acme/widget"}
+	for _, h := range wantHeadings {
+		if !strings.Contains(out, h) {
+			t.Errorf("missing %q in:\n%s", h, out)
+		}
+	}
+	// Repos should be grouped and sorted, so gizmo's heading comes first.
+	if strings.Index(out, "## acme/gizmo") > strings.Index(out, "## acme/widget") {
+		t.Errorf("repos not sorted:\n%s", out)
+	}
+	if !strings.Contains(out, "[#1 Fix race condition]") {
+		t.Errorf("missing issue entry in:\n%s", out)
+	}
+}
+
+func TestRSSWriter(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (rssWriter{}).Write(&buf, sampleIssues()); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, "<feed xmlns=\"http://www.w3.org/2005/Atom\">") {
+		t.Errorf("missing Atom feed element:\n%s", out)
+	}
+	if strings.Count(out, "<entry>") != 2 {
+		t.Errorf("want 2 entries, got:\n%s", out)
+	}
+	if !strings.Contains(out, "<id>https://github.com/acme/widget/issues/1</id>") {
+		t.Errorf("entry id should be the issue URL:\n%s", out)
+	}
+}
+
+func TestSQLiteWriterUpsert(t *testing.T) {
+	dsn := filepath.Join(t.TempDir(), "issues.db")
+	sw := sqliteWriter{DSN: dsn}
+
+	if err := sw.Write(nil, sampleIssues()); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	// Writing again with an updated field should upsert, not duplicate.
+	updated := sampleIssues()
+	updated[0].CommentCount = 5
+	if err := sw.Write(nil, updated); err != nil {
+		t.Fatalf("second Write: %v", err)
+	}
+
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		t.Fatalf("sql.Open: %v", err)
+	}
+	defer db.Close()
+
+	var count, commentCount int
+	if err := db.QueryRow("SELECT COUNT(*) FROM issues").Scan(&count); err != nil {
+		t.Fatalf("count query: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("got %d rows, want 2 (no duplicates across runs)", count)
+	}
+
+	if err := db.QueryRow("SELECT comment_count FROM issues WHERE repository = ? AND number = ?", "acme/widget", 1).Scan(&commentCount); err != nil {
+		t.Fatalf("comment_count query: %v", err)
+	}
+	if commentCount != 5 {
+		t.Errorf("comment_count = %d, want 5 (upsert should apply the update)", commentCount)
+	}
+}
+
+func TestWriterFor(t *testing.T) {
+	for _, format := range []string{"json", "csv", "md", "rss", "sqlite"} {
+		if _, err := writerFor(format); err != nil {
+			t.Errorf("writerFor(%q): %v", format, err)
+		}
+	}
+
+	if _, err := writerFor("yaml"); err == nil {
+		t.Error("writerFor(\"yaml\") should error on an unknown format")
+	}
+}
+
+func TestFormatExtension(t *testing.T) {
+	cases := map[string]string{"json": "json", "csv": "csv", "md": "md", "rss": "xml", "sqlite": "db", "bogus": "json"}
+	for format, want := range cases {
+		if got := formatExtension(format); got != want {
+			t.Errorf("formatExtension(%q) = %q, want %q", format, got, want)
+		}
+	}
+}